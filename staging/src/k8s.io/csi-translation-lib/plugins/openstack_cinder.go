@@ -18,7 +18,10 @@ package plugins
 
 import (
 	"fmt"
+	"strings"
+
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -26,6 +29,28 @@ const (
 	CinderDriverName = "cinder.csi.openstack.org"
 	// CinderInTreePluginName is the name of the intree plugin for Cinder
 	CinderInTreePluginName = "kubernetes.io/cinder"
+
+	// in-tree Cinder StorageClass parameter keys
+	fsTypeKey          = "fstype"
+	volumeTypeKey      = "type"
+	availabilityKey    = "availability"
+	secretNameKey      = "secretRef"
+	secretNamespaceKey = "secretNamespace"
+
+	// CSI Cinder driver StorageClass parameter keys
+	csiFsTypeKey                     = "csi.storage.k8s.io/fstype"
+	csiProvisionerSecretNameKey      = "csi.storage.k8s.io/provisioner-secret-name"
+	csiProvisionerSecretNamespaceKey = "csi.storage.k8s.io/provisioner-secret-namespace"
+
+	// zoneLabel is the legacy in-tree topology label used to constrain Cinder
+	// volumes to a failure domain.
+	zoneLabel = "failure-domain.beta.kubernetes.io/zone"
+	// topologyKey is the topology key advertised by the Cinder CSI driver.
+	topologyKey = "topology.cinder.csi.openstack.org/zone"
+	// zoneVolumeAttribute is the CSI VolumeAttribute under which the
+	// availability zone is surfaced so the external-provisioner can honor
+	// allowedTopologies.
+	zoneVolumeAttribute = "availability"
 )
 
 var _ InTreePlugin = (*osCinderCSITranslator)(nil)
@@ -40,7 +65,22 @@ func NewOpenStackCinderCSITranslator() InTreePlugin {
 
 // TranslateInTreeStorageClassParametersToCSI translates InTree Cinder storage class parameters to CSI storage class
 func (t *osCinderCSITranslator) TranslateInTreeStorageClassParametersToCSI(scParameters map[string]string) (map[string]string, error) {
-	return scParameters, nil
+	var params = map[string]string{}
+	for k, v := range scParameters {
+		switch normalizedKey := strings.ToLower(k); normalizedKey {
+		case fsTypeKey:
+			params[csiFsTypeKey] = v
+		case volumeTypeKey, availabilityKey:
+			params[normalizedKey] = v
+		case secretNameKey:
+			params[csiProvisionerSecretNameKey] = v
+		case secretNamespaceKey:
+			params[csiProvisionerSecretNamespaceKey] = v
+		default:
+			return nil, fmt.Errorf("parameter %q is not supported by the Cinder CSI driver", k)
+		}
+	}
+	return params, nil
 }
 
 // TranslateInTreePVToCSI takes a PV with Cinder set from in-tree
@@ -60,11 +100,46 @@ func (t *osCinderCSITranslator) TranslateInTreePVToCSI(pv *v1.PersistentVolume)
 		VolumeAttributes: map[string]string{},
 	}
 
+	translateTopologyKey(pv.Spec.NodeAffinity, zoneLabel, topologyKey)
+	if zone := topologyZone(pv.Spec.NodeAffinity, topologyKey); zone != "" {
+		csiSource.VolumeAttributes[zoneVolumeAttribute] = zone
+	}
+
 	pv.Spec.Cinder = nil
 	pv.Spec.CSI = csiSource
 	return pv, nil
 }
 
+// TranslateInTreeInlineVolumeToCSI takes a Volume with an in-tree Cinder
+// volume source and converts it to a Volume with a CSI source that can be
+// used in ephemeral, inline-volume contexts.
+func (t *osCinderCSITranslator) TranslateInTreeInlineVolumeToCSI(volume *v1.Volume) (*v1.PersistentVolume, error) {
+	if volume == nil || volume.Cinder == nil {
+		return nil, fmt.Errorf("volume is nil or Cinder not defined on volume")
+	}
+
+	cinderSource := volume.Cinder
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			// Must be unique per disk as it is used as the unique part of the
+			// staging path.
+			Name: fmt.Sprintf("%s-%s", CinderDriverName, cinderSource.VolumeID),
+		},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{
+					Driver:           CinderDriverName,
+					VolumeHandle:     cinderSource.VolumeID,
+					ReadOnly:         cinderSource.ReadOnly,
+					FSType:           cinderSource.FSType,
+					VolumeAttributes: map[string]string{},
+				},
+			},
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+		},
+	}, nil
+}
+
 // TranslateCSIPVToInTree takes a PV with CSIPersistentVolumeSource set and
 // translates the Cinder CSI source to a Cinder In-tree source.
 func (t *osCinderCSITranslator) TranslateCSIPVToInTree(pv *v1.PersistentVolume) (*v1.PersistentVolume, error) {
@@ -80,6 +155,8 @@ func (t *osCinderCSITranslator) TranslateCSIPVToInTree(pv *v1.PersistentVolume)
 		ReadOnly: csiSource.ReadOnly,
 	}
 
+	translateTopologyKey(pv.Spec.NodeAffinity, topologyKey, zoneLabel)
+
 	pv.Spec.CSI = nil
 	pv.Spec.Cinder = cinderSource
 	return pv, nil
@@ -96,3 +173,35 @@ func (t *osCinderCSITranslator) CanSupport(pv *v1.PersistentVolume) bool {
 func (t *osCinderCSITranslator) GetInTreePluginName() string {
 	return CinderInTreePluginName
 }
+
+// translateTopologyKey rewrites, in place, every NodeSelectorRequirement key
+// matching oldKey in nodeAffinity's required terms to newKey.
+func translateTopologyKey(nodeAffinity *v1.VolumeNodeAffinity, oldKey, newKey string) {
+	if nodeAffinity == nil || nodeAffinity.Required == nil {
+		return
+	}
+	for i := range nodeAffinity.Required.NodeSelectorTerms {
+		term := &nodeAffinity.Required.NodeSelectorTerms[i]
+		for j := range term.MatchExpressions {
+			if term.MatchExpressions[j].Key == oldKey {
+				term.MatchExpressions[j].Key = newKey
+			}
+		}
+	}
+}
+
+// topologyZone returns the first value associated with key in nodeAffinity's
+// required terms, or the empty string if key is not present.
+func topologyZone(nodeAffinity *v1.VolumeNodeAffinity, key string) string {
+	if nodeAffinity == nil || nodeAffinity.Required == nil {
+		return ""
+	}
+	for _, term := range nodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == key && len(expr.Values) > 0 {
+				return expr.Values[0]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+)
+
+func TestTranslateInTreeStorageClassParametersToCSI(t *testing.T) {
+	translator := NewOpenStackCinderCSITranslator()
+
+	tcs := []struct {
+		name       string
+		options    map[string]string
+		expOptions map[string]string
+		expErr     bool
+	}{
+		{
+			name:       "fstype is renamed to the CSI key",
+			options:    map[string]string{"fstype": "ext4"},
+			expOptions: map[string]string{csiFsTypeKey: "ext4"},
+		},
+		{
+			name:       "type and availability pass through unchanged",
+			options:    map[string]string{"type": "fast", "availability": "nova"},
+			expOptions: map[string]string{"type": "fast", "availability": "nova"},
+		},
+		{
+			name:       "secretRef and secretNamespace are translated to CSI secret keys",
+			options:    map[string]string{"secretRef": "my-secret", "secretNamespace": "my-ns"},
+			expOptions: map[string]string{csiProvisionerSecretNameKey: "my-secret", csiProvisionerSecretNamespaceKey: "my-ns"},
+		},
+		{
+			name:       "mixed-case keys are matched case-insensitively and normalized",
+			options:    map[string]string{"Type": "fast", "Availability": "nova"},
+			expOptions: map[string]string{"type": "fast", "availability": "nova"},
+		},
+		{
+			name:    "unsupported parameters are rejected",
+			options: map[string]string{"foo": "bar"},
+			expErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			gotOptions, err := translator.TranslateInTreeStorageClassParametersToCSI(tc.options)
+			if err != nil != tc.expErr {
+				t.Errorf("got error: %v, but expected error: %t", err, tc.expErr)
+			}
+			if tc.expErr {
+				return
+			}
+			if !reflect.DeepEqual(gotOptions, tc.expOptions) {
+				t.Errorf("got parameters: %v, expected parameters: %v", gotOptions, tc.expOptions)
+			}
+		})
+	}
+}
+
+func TestTranslateInTreePVToCSI(t *testing.T) {
+	translator := NewOpenStackCinderCSITranslator()
+
+	cases := []struct {
+		name string
+		pv   *v1.PersistentVolume
+	}{
+		{
+			name: "normal",
+			pv: &v1.PersistentVolume{
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeSource: v1.PersistentVolumeSource{
+						Cinder: &v1.CinderPersistentVolumeSource{
+							VolumeID: "test-volume-id",
+							FSType:   "ext4",
+							ReadOnly: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := translator.TranslateInTreePVToCSI(tc.pv)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if got.Spec.Cinder != nil {
+				t.Error("expected in-tree Cinder source to be cleared after translation")
+			}
+
+			if got.Spec.CSI == nil {
+				t.Fatal("expected CSI source to be set after translation")
+			}
+
+			back, err := translator.TranslateCSIPVToInTree(got)
+			if err != nil {
+				t.Errorf("unexpected error translating back to in-tree: %v", err)
+			}
+
+			if back.Spec.CSI != nil {
+				t.Error("expected CSI source to be cleared after translating back to in-tree")
+			}
+
+			if back.Spec.Cinder == nil {
+				t.Fatal("expected in-tree Cinder source to be restored")
+			}
+
+			if back.Spec.Cinder.VolumeID != "test-volume-id" || back.Spec.Cinder.FSType != "ext4" || !back.Spec.Cinder.ReadOnly {
+				t.Errorf("round trip through CSI did not preserve the original Cinder source: %+v", back.Spec.Cinder)
+			}
+		})
+	}
+}
+
+func nodeAffinityWithZone(key, zone string) *v1.VolumeNodeAffinity {
+	return &v1.VolumeNodeAffinity{
+		Required: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{
+					MatchExpressions: []v1.NodeSelectorRequirement{
+						{
+							Key:      key,
+							Operator: v1.NodeSelectorOpIn,
+							Values:   []string{zone},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTranslateTopologyInTreePVToCSI(t *testing.T) {
+	translator := NewOpenStackCinderCSITranslator()
+
+	pv := &v1.PersistentVolume{
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				Cinder: &v1.CinderPersistentVolumeSource{VolumeID: "test-volume-id"},
+			},
+			NodeAffinity: nodeAffinityWithZone(zoneLabel, "nova"),
+		},
+	}
+
+	got, err := translator.TranslateInTreePVToCSI(pv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if zone := topologyZone(got.Spec.NodeAffinity, topologyKey); zone != "nova" {
+		t.Errorf("expected node affinity to use the CSI topology key with zone %q, got %q", "nova", zone)
+	}
+
+	if got.Spec.CSI.VolumeAttributes[zoneVolumeAttribute] != "nova" {
+		t.Errorf("expected availability zone to be propagated to VolumeAttributes, got %v", got.Spec.CSI.VolumeAttributes)
+	}
+
+	back, err := translator.TranslateCSIPVToInTree(got)
+	if err != nil {
+		t.Fatalf("unexpected error translating back to in-tree: %v", err)
+	}
+
+	if zone := topologyZone(back.Spec.NodeAffinity, zoneLabel); zone != "nova" {
+		t.Errorf("expected node affinity to restore the legacy zone label with zone %q, got %q", "nova", zone)
+	}
+}
+
+func TestTranslateInTreeInlineVolumeToCSI(t *testing.T) {
+	translator := NewOpenStackCinderCSITranslator()
+
+	cases := []struct {
+		name   string
+		volume *v1.Volume
+		expErr bool
+	}{
+		{
+			name:   "volume is nil",
+			volume: nil,
+			expErr: true,
+		},
+		{
+			name:   "volume has no Cinder source",
+			volume: &v1.Volume{},
+			expErr: true,
+		},
+		{
+			name: "normal",
+			volume: &v1.Volume{
+				VolumeSource: v1.VolumeSource{
+					Cinder: &v1.CinderVolumeSource{
+						VolumeID: "test-volume-id",
+						FSType:   "ext4",
+						ReadOnly: true,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := translator.TranslateInTreeInlineVolumeToCSI(tc.volume)
+			if err != nil != tc.expErr {
+				t.Errorf("got error: %v, but expected error: %t", err, tc.expErr)
+			}
+			if tc.expErr {
+				return
+			}
+
+			if got.Spec.CSI == nil {
+				t.Fatal("expected CSI source to be set")
+			}
+
+			if got.Spec.CSI.VolumeHandle != "test-volume-id" || got.Spec.CSI.FSType != "ext4" || !got.Spec.CSI.ReadOnly {
+				t.Errorf("CSI source does not match original Cinder volume source: %+v", got.Spec.CSI)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package windows
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,13 +30,32 @@ import (
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
 	"k8s.io/kubernetes/test/e2e/framework"
+	"k8s.io/kubernetes/test/e2e/perftype"
 	imageutils "k8s.io/kubernetes/test/utils/image"
 
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
 )
 
+// currentDensityPerfMetricsVersion is the version tag attached to the perf
+// data this suite emits, so dashboards can tell incompatible formats apart.
+const currentDensityPerfMetricsVersion = "v1"
+
+// defaultResourceCollectInterval is used to poll node resource usage when
+// densityTest.resourceCollectInterval is unset.
+const defaultResourceCollectInterval = 10 * time.Second
+
+// densityResourceMonitorContainers are the system containers whose CPU and
+// memory usage are tracked for pressure during a density run. These are the
+// fixed container names the kubelet reports via /stats/summary (the same
+// ones framework.TargetContainers uses for the equivalent Linux density and
+// resource-usage tests): the kubelet process itself, the container runtime,
+// other host-level processes, and the aggregate of all pause containers.
+var densityResourceMonitorContainers = framework.TargetContainers()
+
 var _ = SIGDescribe("[Feature:Windows] Density [Serial] [Slow]", func() {
 
 	f := framework.NewDefaultFramework("density-test-windows")
@@ -52,25 +72,91 @@ var _ = SIGDescribe("[Feature:Windows] Density [Serial] [Slow]", func() {
 			{
 				podsNr:   10,
 				interval: 0 * time.Millisecond,
-				// percentile limit of single pod startup latency
+				// percentile limit of CPU usage (in cores) per tracked system container
+				cpuLimits: framework.ContainersCPUSummary{
+					stats.SystemContainerKubelet: {0.50: 0.35, 0.90: 0.50, 0.99: 0.60},
+					stats.SystemContainerRuntime: {0.50: 0.25, 0.90: 0.40, 0.99: 0.50},
+				},
+				// memory usage limit per tracked system container
+				memLimits: framework.ResourceUsagePerContainer{
+					stats.SystemContainerKubelet: &framework.ContainerResourceUsage{MemoryRSSInBytes: 200 * 1024 * 1024},
+					stats.SystemContainerRuntime: &framework.ContainerResourceUsage{MemoryRSSInBytes: 300 * 1024 * 1024},
+				},
+				// percentile limit of create->schedule latency (scheduler): a
+				// small slice of the end-to-end budget below, since apiserver
+				// admission and scheduling are not expected to dominate.
+				scheduleLimits: framework.LatencyMetric{
+					Perc50: 5 * time.Second,
+					Perc90: 9 * time.Second,
+					Perc99: 10 * time.Second,
+				},
+				// percentile limit of schedule->run latency (kubelet/CRI): the
+				// dominant slice, since this covers image pull and container
+				// start, which are comparatively slow on Windows nodes.
+				runLimits: framework.LatencyMetric{
+					Perc50: 20 * time.Second,
+					Perc90: 36 * time.Second,
+					Perc99: 39 * time.Second,
+				},
+				// percentile limit of run->watch latency (CRI status/informer):
+				// a small slice, since this is just status propagation.
+				//
+				// The three phase limits above sum to the former single
+				// end-to-end podStartupLimits of 30s/54s/59s, which used to
+				// cover the entire create->watch path before it was split into
+				// these per-phase budgets.
 				podStartupLimits: framework.LatencyMetric{
-					Perc50: 30 * time.Second,
-					Perc90: 54 * time.Second,
-					Perc99: 59 * time.Second,
+					Perc50: 5 * time.Second,
+					Perc90: 9 * time.Second,
+					Perc99: 10 * time.Second,
 				},
 				// upbound of startup latency of a batch of pods
 				podBatchStartupLimit: 10 * time.Minute,
 			},
+			{
+				podsNr:      10,
+				bgPodsNr:    50,
+				interval:    0 * time.Millisecond,
+				APIQPSLimit: 20,
+				scheduleLimits: framework.LatencyMetric{
+					Perc50: 5 * time.Second,
+					Perc90: 9 * time.Second,
+					Perc99: 10 * time.Second,
+				},
+				runLimits: framework.LatencyMetric{
+					Perc50: 20 * time.Second,
+					Perc90: 36 * time.Second,
+					Perc99: 39 * time.Second,
+				},
+				podStartupLimits: framework.LatencyMetric{
+					Perc50: 5 * time.Second,
+					Perc90: 9 * time.Second,
+					Perc99: 10 * time.Second,
+				},
+				podBatchStartupLimit: 10 * time.Minute,
+			},
 		}
 
 		for _, testArg := range dTests {
-			itArg := testArg
-			desc := fmt.Sprintf("latency/resource should be within limit when create %d pods with %v interval", itArg.podsNr, itArg.interval)
-			ginkgo.It(desc, func() {
+			// createMethod "batch" ignores bgPodsNr/APIQPSLimit, so entries that only
+			// exist to exercise the background-load/QPS-throttled path would otherwise
+			// produce a batch spec identical to, and indistinguishable from, the plain
+			// entry's. Skip it for those entries; every entry still gets its sequence
+			// variant, since that desc is already disambiguated by bgPodsNr/APIQPSLimit.
+			if testArg.bgPodsNr == 0 && testArg.APIQPSLimit == 0 {
+				itArg := testArg
 				itArg.createMethod = "batch"
+				desc := fmt.Sprintf("latency/resource should be within limit when create %d pods with %v interval", itArg.podsNr, itArg.interval)
+				ginkgo.It(desc, func() {
+					runDensityBatchTest(f, itArg)
+				})
+			}
 
-				runDensityBatchTest(f, itArg)
-
+			seqArg := testArg
+			seqArg.createMethod = "sequence"
+			seqDesc := fmt.Sprintf("latency/resource should be within limit when create %d pods with %d background pods and %d QPS limit at %v interval [Sequence]", seqArg.podsNr, seqArg.bgPodsNr, seqArg.APIQPSLimit, seqArg.interval)
+			ginkgo.It(seqDesc, func() {
+				runDensitySeqTest(f, seqArg)
 			})
 		}
 	})
@@ -88,15 +174,23 @@ type densityTest struct {
 	createMethod string
 	// API QPS limit
 	APIQPSLimit int
+	// polling interval for node resource usage collection, defaults to
+	// defaultResourceCollectInterval when zero
+	resourceCollectInterval time.Duration
 	// performance limits
-	cpuLimits            framework.ContainersCPUSummary
-	memLimits            framework.ResourceUsagePerContainer
+	cpuLimits framework.ContainersCPUSummary
+	memLimits framework.ResourceUsagePerContainer
+	// per-phase percentile limits, attributing regressions to the
+	// scheduler (scheduleLimits), the kubelet (runLimits), or the
+	// CRI/watch delivery path (podStartupLimits)
+	scheduleLimits       framework.LatencyMetric
+	runLimits            framework.LatencyMetric
 	podStartupLimits     framework.LatencyMetric
 	podBatchStartupLimit time.Duration
 }
 
 // runDensityBatchTest runs the density batch pod creation test
-func runDensityBatchTest(f *framework.Framework, testArg densityTest) (time.Duration, []framework.PodLatencyData) {
+func runDensityBatchTest(f *framework.Framework, testArg densityTest) (time.Duration, phaseLatencyData) {
 	const (
 		podType = "density_test_pod"
 	)
@@ -114,6 +208,9 @@ func runDensityBatchTest(f *framework.Framework, testArg densityTest) (time.Dura
 	go controller.Run(stopCh)
 	defer close(stopCh)
 
+	rm := newDensityResourceMonitor(f, testArg)
+	rm.Start()
+
 	ginkgo.By("Creating a batch of pods")
 	// It returns a map['pod name']'creation time' containing the creation timestamps
 	createTimes := createBatchPodWithRateControl(f, pods, testArg.interval)
@@ -128,21 +225,136 @@ func runDensityBatchTest(f *framework.Framework, testArg densityTest) (time.Dura
 		framework.Failf("Timeout reached waiting for all Pods to be observed by the watch.")
 	}
 
-	// Analyze results
+	rm.Stop()
+	batchLag, lags := computePodPhaseLatency(f, createTimes, watchTimes)
+	logDensityLatencyPerfData(lags, testArg)
+	verifyDensityResourceUsage(rm, testArg)
+	verifyPhaseLatency(lags, testArg)
+
+	deletePodsSync(f, pods)
+
+	return batchLag, lags
+}
+
+// runDensitySeqTest starts testArg.bgPodsNr background pods, waits for them
+// to reach Running, then creates the foreground pods one at a time (subject
+// to testArg.APIQPSLimit) and measures their startup latency.
+func runDensitySeqTest(f *framework.Framework, testArg densityTest) (time.Duration, phaseLatencyData) {
+	const (
+		podType   = "density_test_pod"
+		bgPodType = "background_pod"
+	)
+	var (
+		mutex      = &sync.Mutex{}
+		watchTimes = make(map[string]metav1.Time, 0)
+		bgMutex    = &sync.Mutex{}
+		bgWatch    = make(map[string]metav1.Time, 0)
+		stopCh     = make(chan struct{})
+		bgStopCh   = make(chan struct{})
+	)
+
+	bgPods := newTestPods(testArg.bgPodsNr, false, imageutils.GetPauseImageName(), bgPodType)
+	pods := newTestPods(testArg.podsNr, false, imageutils.GetPauseImageName(), podType)
+
+	bgController := newInformerWatchPod(f, bgMutex, bgWatch, bgPodType)
+	go bgController.Run(bgStopCh)
+	defer close(bgStopCh)
+
+	ginkgo.By("Creating a batch of background pods")
+	for _, pod := range bgPods {
+		go f.PodClient().Create(pod)
+	}
+
+	ginkgo.By("Waiting for all background Pods to be running...")
+	gomega.Eventually(func() int {
+		bgMutex.Lock()
+		defer bgMutex.Unlock()
+		return len(bgWatch)
+	}, 10*time.Minute, 10*time.Second).Should(gomega.Equal(testArg.bgPodsNr))
+
+	controller := newInformerWatchPod(f, mutex, watchTimes, podType)
+	go controller.Run(stopCh)
+	defer close(stopCh)
+
+	rm := newDensityResourceMonitor(f, testArg)
+	rm.Start()
+
+	ginkgo.By("Creating a sequence of pods")
+	createTimes := createSequentialPodWithRateControl(f, pods, testArg.APIQPSLimit)
+
+	ginkgo.By("Waiting for all Pods to be observed by the watch...")
+	gomega.Eventually(func() bool {
+		return len(watchTimes) == testArg.podsNr
+	}, 10*time.Minute, 10*time.Second).Should(gomega.BeTrue())
+
+	if len(watchTimes) < testArg.podsNr {
+		framework.Failf("Timeout reached waiting for all Pods to be observed by the watch.")
+	}
+
+	rm.Stop()
+	batchLag, lags := computePodPhaseLatency(f, createTimes, watchTimes)
+	logDensityLatencyPerfData(lags, testArg)
+	verifyDensityResourceUsage(rm, testArg)
+	verifyPhaseLatency(lags, testArg)
+
+	deletePodsSync(f, append(bgPods, pods...))
+
+	return batchLag, lags
+}
+
+// phaseLatencyData holds the sorted per-pod latencies for each phase of pod
+// startup, so a regression can be attributed to the scheduler, the kubelet,
+// or the CRI/watch delivery path instead of a single lumped number.
+type phaseLatencyData struct {
+	// scheduleLags is create->schedule latency (apiserver + scheduler)
+	scheduleLags []framework.PodLatencyData
+	// runLags is schedule->run latency (kubelet + CRI)
+	runLags []framework.PodLatencyData
+	// watchLags is run->watch latency (CRI status propagation + informer delivery)
+	watchLags []framework.PodLatencyData
+	// e2eLags is the overall create->watch latency, kept for the batch-level limit
+	e2eLags []framework.PodLatencyData
+}
+
+// computePodPhaseLatency reads each pod's own PodScheduled condition and
+// Status.StartTime to break down startup latency into create->schedule,
+// schedule->run, and run->watch phases, in addition to the overall batch lag.
+func computePodPhaseLatency(f *framework.Framework, createTimes, watchTimes map[string]metav1.Time) (time.Duration, phaseLatencyData) {
 	var (
 		firstCreate metav1.Time
 		lastRunning metav1.Time
 		init        = true
-		e2eLags     = make([]framework.PodLatencyData, 0)
+		lags        phaseLatencyData
 	)
 
 	for name, create := range createTimes {
 		watch, ok := watchTimes[name]
 		gomega.Expect(ok).To(gomega.Equal(true))
 
-		e2eLags = append(e2eLags,
+		pod, err := f.ClientSet.CoreV1().Pods(f.Namespace.Name).Get(name, metav1.GetOptions{})
+		framework.ExpectNoError(err)
+
+		lags.e2eLags = append(lags.e2eLags,
 			framework.PodLatencyData{Name: name, Latency: watch.Time.Sub(create.Time)})
 
+		scheduled := podScheduledTime(pod)
+		run := pod.Status.StartTime
+
+		if scheduled != nil {
+			lags.scheduleLags = append(lags.scheduleLags,
+				framework.PodLatencyData{Name: name, Latency: scheduled.Time.Sub(create.Time)})
+
+			if run != nil {
+				lags.runLags = append(lags.runLags,
+					framework.PodLatencyData{Name: name, Latency: run.Time.Sub(scheduled.Time)})
+			}
+		}
+
+		if run != nil {
+			lags.watchLags = append(lags.watchLags,
+				framework.PodLatencyData{Name: name, Latency: watch.Time.Sub(run.Time)})
+		}
+
 		if !init {
 			if firstCreate.Time.After(create.Time) {
 				firstCreate = create
@@ -156,12 +368,223 @@ func runDensityBatchTest(f *framework.Framework, testArg densityTest) (time.Dura
 		}
 	}
 
-	sort.Sort(framework.LatencySlice(e2eLags))
-	batchLag := lastRunning.Time.Sub(firstCreate.Time)
+	sort.Sort(framework.LatencySlice(lags.e2eLags))
+	sort.Sort(framework.LatencySlice(lags.scheduleLags))
+	sort.Sort(framework.LatencySlice(lags.runLags))
+	sort.Sort(framework.LatencySlice(lags.watchLags))
 
-	deletePodsSync(f, pods)
+	return lastRunning.Time.Sub(firstCreate.Time), lags
+}
 
-	return batchLag, e2eLags
+// podScheduledTime returns the LastTransitionTime of pod's PodScheduled
+// condition, or nil if the pod has not been scheduled.
+func podScheduledTime(pod *v1.Pod) *metav1.Time {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodScheduled && c.Status == v1.ConditionTrue {
+			return &c.LastTransitionTime
+		}
+	}
+	return nil
+}
+
+// verifyPhaseLatency fails the test if any phase's observed percentiles
+// exceed its corresponding limit in testArg, printing a per-phase table
+// first so a regression can be attributed to the right layer.
+func verifyPhaseLatency(lags phaseLatencyData, testArg densityTest) {
+	scheduleMetric := framework.ExtractLatencyMetrics(lags.scheduleLags)
+	runMetric := framework.ExtractLatencyMetrics(lags.runLags)
+	watchMetric := framework.ExtractLatencyMetrics(lags.watchLags)
+
+	scheduleErr := verifyLatencyMetric(scheduleMetric, testArg.scheduleLimits)
+	runErr := verifyLatencyMetric(runMetric, testArg.runLimits)
+	watchErr := verifyLatencyMetric(watchMetric, testArg.podStartupLimits)
+
+	if scheduleErr != nil || runErr != nil || watchErr != nil {
+		framework.Logf("Pod startup latency breakdown (Perc50/Perc90/Perc99):\n"+
+			"  create->schedule (scheduler):     %v / %v / %v\n"+
+			"  schedule->run (kubelet/CRI):      %v / %v / %v\n"+
+			"  run->watch (CRI status/informer): %v / %v / %v",
+			scheduleMetric.Perc50, scheduleMetric.Perc90, scheduleMetric.Perc99,
+			runMetric.Perc50, runMetric.Perc90, runMetric.Perc99,
+			watchMetric.Perc50, watchMetric.Perc90, watchMetric.Perc99)
+	}
+
+	framework.ExpectNoError(scheduleErr, "create->schedule latency exceeded scheduleLimits")
+	framework.ExpectNoError(runErr, "schedule->run latency exceeded runLimits")
+	framework.ExpectNoError(watchErr, "run->watch latency exceeded podStartupLimits")
+}
+
+// verifyLatencyMetric returns an error if any percentile in metric exceeds
+// the corresponding percentile in limit. A zero-valued limit disables the check.
+func verifyLatencyMetric(metric, limit framework.LatencyMetric) error {
+	if limit == (framework.LatencyMetric{}) {
+		return nil
+	}
+	if metric.Perc50 > limit.Perc50 || metric.Perc90 > limit.Perc90 || metric.Perc99 > limit.Perc99 {
+		return fmt.Errorf("got percentiles {Perc50: %v, Perc90: %v, Perc99: %v}, want at most {Perc50: %v, Perc90: %v, Perc99: %v}",
+			metric.Perc50, metric.Perc90, metric.Perc99, limit.Perc50, limit.Perc90, limit.Perc99)
+	}
+	return nil
+}
+
+// logDensityLatencyPerfData reports each phase's percentile latency metrics
+// as Prometheus-style perf data so results can be diffed across runs.
+func logDensityLatencyPerfData(lags phaseLatencyData, testArg densityTest) {
+	phases := []struct {
+		name    string
+		latency []framework.PodLatencyData
+	}{
+		{"create-to-schedule", lags.scheduleLags},
+		{"schedule-to-run", lags.runLags},
+		{"run-to-watch", lags.watchLags},
+		{"create-to-watch", lags.e2eLags},
+	}
+
+	items := make([]perftype.DataItem, 0, len(phases))
+	for _, phase := range phases {
+		metric := framework.ExtractLatencyMetrics(phase.latency)
+		framework.Logf("phase %s: Perc50: %v, Perc90: %v, Perc99: %v", phase.name, metric.Perc50, metric.Perc90, metric.Perc99)
+
+		items = append(items, perftype.DataItem{
+			Data: map[string]float64{
+				"Perc50": metric.Perc50.Seconds(),
+				"Perc90": metric.Perc90.Seconds(),
+				"Perc99": metric.Perc99.Seconds(),
+			},
+			Unit: "s",
+			Labels: map[string]string{
+				"datatype":     "latency",
+				"latencytype":  phase.name,
+				"createMethod": testArg.createMethod,
+			},
+		})
+	}
+
+	framework.PrintPerfData(&perftype.PerfData{
+		Version:   currentDensityPerfMetricsVersion,
+		DataItems: items,
+	})
+}
+
+// newDensityResourceMonitor creates a resource monitor polling
+// densityResourceMonitorContainers at testArg.resourceCollectInterval (or
+// defaultResourceCollectInterval if unset).
+func newDensityResourceMonitor(f *framework.Framework, testArg densityTest) *framework.ResourceMonitor {
+	interval := testArg.resourceCollectInterval
+	if interval <= 0 {
+		interval = defaultResourceCollectInterval
+	}
+	return framework.NewResourceMonitor(f.ClientSet, densityResourceMonitorContainers, interval)
+}
+
+// verifyDensityResourceUsage fails the test if the CPU or memory usage
+// collected by rm exceeds the percentile/point limits declared on testArg,
+// and reports the collected series as perf data.
+func verifyDensityResourceUsage(rm *framework.ResourceMonitor, testArg densityTest) {
+	rm.LogLatest()
+	rm.LogCPUSummary()
+
+	nodesCPUSummary := rm.GetCPUSummary()
+	usagePerNode, err := rm.GetLatest()
+	framework.ExpectNoError(err)
+
+	for nodeName, containersCPU := range nodesCPUSummary {
+		if len(testArg.cpuLimits) > 0 {
+			framework.ExpectNoError(verifyCPULimits(testArg.cpuLimits, containersCPU))
+		}
+		logDensityResourcePerfData(containersCPU, usagePerNode[nodeName], nodeName, testArg)
+	}
+
+	if len(testArg.memLimits) > 0 {
+		for _, containersUsage := range usagePerNode {
+			framework.ExpectNoError(verifyMemoryLimits(testArg.memLimits, containersUsage))
+		}
+	}
+}
+
+// verifyCPULimits fails if any percentile in actual exceeds the
+// corresponding limit declared in expected.
+func verifyCPULimits(expected framework.ContainersCPUSummary, actual framework.ContainersCPUSummary) error {
+	if expected == nil {
+		return nil
+	}
+	var errList []string
+	for containerName, limits := range expected {
+		containerUsage, ok := actual[containerName]
+		if !ok {
+			errList = append(errList, fmt.Sprintf("container %q not found in actual CPU usage", containerName))
+			continue
+		}
+		for perc, limit := range limits {
+			usage, ok := containerUsage[perc]
+			if !ok {
+				continue
+			}
+			if usage > limit {
+				errList = append(errList, fmt.Sprintf("container %q exceeded CPU limit at percentile %v: got %f, want <= %f", containerName, perc, usage, limit))
+			}
+		}
+	}
+	if len(errList) > 0 {
+		return fmt.Errorf("CPU usage exceeded limits:\n%s", strings.Join(errList, "\n"))
+	}
+	return nil
+}
+
+// verifyMemoryLimits fails if any container's observed memory usage in
+// actual exceeds the corresponding limit declared in expected.
+func verifyMemoryLimits(expected framework.ResourceUsagePerContainer, actual framework.ResourceUsagePerContainer) error {
+	if expected == nil {
+		return nil
+	}
+	var errList []string
+	for containerName, limit := range expected {
+		usage, ok := actual[containerName]
+		if !ok || limit == nil || usage == nil {
+			continue
+		}
+		if limit.MemoryRSSInBytes > 0 && usage.MemoryRSSInBytes > limit.MemoryRSSInBytes {
+			errList = append(errList, fmt.Sprintf("container %q exceeded memory RSS limit: got %d, want <= %d", containerName, usage.MemoryRSSInBytes, limit.MemoryRSSInBytes))
+		}
+		if limit.MemoryWorkingSetInBytes > 0 && usage.MemoryWorkingSetInBytes > limit.MemoryWorkingSetInBytes {
+			errList = append(errList, fmt.Sprintf("container %q exceeded memory working set limit: got %d, want <= %d", containerName, usage.MemoryWorkingSetInBytes, limit.MemoryWorkingSetInBytes))
+		}
+	}
+	if len(errList) > 0 {
+		return fmt.Errorf("memory usage exceeded limits:\n%s", strings.Join(errList, "\n"))
+	}
+	return nil
+}
+
+// logDensityResourcePerfData reports the CPU percentiles and latest memory
+// usage collected for nodeName as Prometheus-style perf data.
+func logDensityResourcePerfData(cpuSummary framework.ContainersCPUSummary, usage framework.ResourceUsagePerContainer, nodeName string, testArg densityTest) {
+	items := make([]perftype.DataItem, 0, len(cpuSummary))
+	for containerName, percentiles := range cpuSummary {
+		data := make(map[string]float64, len(percentiles)+2)
+		for perc, value := range percentiles {
+			data[fmt.Sprintf("Perc%.0f", perc*100)] = value
+		}
+		if containerUsage, ok := usage[containerName]; ok {
+			data["memoryRSS"] = float64(containerUsage.MemoryRSSInBytes)
+			data["memoryWorkingSet"] = float64(containerUsage.MemoryWorkingSetInBytes)
+		}
+		items = append(items, perftype.DataItem{
+			Data: data,
+			Unit: "cores/bytes",
+			Labels: map[string]string{
+				"datatype":     "resource",
+				"node":         nodeName,
+				"container":    containerName,
+				"createMethod": testArg.createMethod,
+			},
+		})
+	}
+
+	framework.PrintPerfData(&perftype.PerfData{
+		Version:   currentDensityPerfMetricsVersion,
+		DataItems: items,
+	})
 }
 
 // createBatchPodWithRateControl creates a batch of pods concurrently, uses one goroutine for each creation.
@@ -176,6 +599,27 @@ func createBatchPodWithRateControl(f *framework.Framework, pods []*v1.Pod, inter
 	return createTimes
 }
 
+// createSequentialPodWithRateControl creates pods one at a time, throttling
+// the creation rate to at most apiQPSLimit requests/sec (unthrottled if
+// apiQPSLimit is 0).
+func createSequentialPodWithRateControl(f *framework.Framework, pods []*v1.Pod, apiQPSLimit int) map[string]metav1.Time {
+	createTimes := make(map[string]metav1.Time)
+
+	var limiter flowcontrol.RateLimiter
+	if apiQPSLimit > 0 {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(float32(apiQPSLimit), apiQPSLimit)
+	}
+
+	for _, pod := range pods {
+		if limiter != nil {
+			limiter.Accept()
+		}
+		createTimes[pod.ObjectMeta.Name] = metav1.Now()
+		f.PodClient().Create(pod)
+	}
+	return createTimes
+}
+
 // newInformerWatchPod creates an informer to check whether all pods are running.
 func newInformerWatchPod(f *framework.Framework, mutex *sync.Mutex, watchTimes map[string]metav1.Time, podType string) cache.Controller {
 	ns := f.Namespace.Name